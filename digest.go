@@ -0,0 +1,231 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+)
+
+// This file implements the Content-Digest and Repr-Digest header fields
+// defined by RFC 9530.
+
+var (
+	// ErrDigestMismatch is returned by VerifyDigests when content does
+	// not match a digest.
+	ErrDigestMismatch = errors.New("http: digest mismatch")
+
+	// ErrNoSupportedDigest is returned by VerifyDigests when none of the
+	// digests uses a supported algorithm.
+	ErrNoSupportedDigest = errors.New("http: no supported digest algorithm")
+
+	errBadDigest            = errors.New("http: malformed digest field")
+	errUnsupportedDigestAlg = errors.New("http: unsupported digest algorithm")
+)
+
+// A Digest is a single member of a Content-Digest or Repr-Digest field,
+// such as sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:.
+type Digest struct {
+	Algorithm string // lower case, e.g. "sha-256"
+	Value     []byte
+}
+
+// digestAlgorithms holds the supported algorithms from the
+// Hash Algorithms for HTTP Digest Fields registry.
+// The deprecated algorithms are not supported.
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// ParseDigests parses a Content-Digest or Repr-Digest field value.
+// Members with unknown algorithms are returned as well, so callers can
+// decide how to treat them. As in any RFC 8941 dictionary, a repeated
+// algorithm replaces the earlier value, and parameters are ignored.
+func ParseDigests(v string) ([]Digest, error) {
+	if strings.Trim(v, " \t") == "" {
+		return nil, nil
+	}
+	members, ok := splitSFDictionary(v)
+	if !ok {
+		return nil, errBadDigest
+	}
+	var digests []Digest
+	index := make(map[string]int)
+	for _, m := range members {
+		alg, value, ok := strings.Cut(m, "=")
+		if !ok || !validSFKey(alg) {
+			return nil, errBadDigest
+		}
+		b, ok := parseSFByteSequence(value)
+		if !ok {
+			return nil, errBadDigest
+		}
+		d := Digest{Algorithm: alg, Value: b}
+		if i, ok := index[alg]; ok {
+			digests[i] = d
+		} else {
+			index[alg] = len(digests)
+			digests = append(digests, d)
+		}
+	}
+	return digests, nil
+}
+
+// FormatDigests returns digests formatted as a Content-Digest or
+// Repr-Digest field value. It returns an error if an algorithm is not
+// a valid field key.
+func FormatDigests(digests ...Digest) (string, error) {
+	var b strings.Builder
+	for i, d := range digests {
+		if !validSFKey(d.Algorithm) {
+			return "", errBadDigest
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(d.Algorithm)
+		b.WriteString("=:")
+		b.WriteString(base64.StdEncoding.EncodeToString(d.Value))
+		b.WriteByte(':')
+	}
+	return b.String(), nil
+}
+
+// ComputeDigests reads r to EOF and returns its digest for each of the
+// given algorithms, computed in a single pass.
+func ComputeDigests(r io.Reader, algorithms ...string) ([]Digest, error) {
+	hashes := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, alg := range algorithms {
+		newHash, ok := digestAlgorithms[alg]
+		if !ok {
+			return nil, errUnsupportedDigestAlg
+		}
+		hashes[i] = newHash()
+		writers[i] = hashes[i]
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return nil, err
+	}
+	digests := make([]Digest, len(algorithms))
+	for i, alg := range algorithms {
+		digests[i] = Digest{Algorithm: alg, Value: hashes[i].Sum(nil)}
+	}
+	return digests, nil
+}
+
+// VerifyDigests reads r to EOF and checks it against every digest that
+// uses a supported algorithm, ignoring the rest. It returns
+// ErrDigestMismatch if any of them differs, and ErrNoSupportedDigest if
+// there is nothing to check.
+func VerifyDigests(r io.Reader, digests []Digest) error {
+	var want []Digest
+	var algorithms []string
+	for _, d := range digests {
+		if _, ok := digestAlgorithms[d.Algorithm]; ok {
+			want = append(want, d)
+			algorithms = append(algorithms, d.Algorithm)
+		}
+	}
+	if len(want) == 0 {
+		return ErrNoSupportedDigest
+	}
+	got, err := ComputeDigests(r, algorithms...)
+	if err != nil {
+		return err
+	}
+	for i := range want {
+		if subtle.ConstantTimeCompare(got[i].Value, want[i].Value) != 1 {
+			return ErrDigestMismatch
+		}
+	}
+	return nil
+}
+
+// splitSFDictionary splits an RFC 8941 dictionary into its members,
+// each with surrounding whitespace and parameters removed. Commas and
+// semicolons inside quoted strings and inner lists do not separate
+// members or parameters. It reports false if a quoted string or an
+// inner list is not terminated.
+func splitSFDictionary(v string) ([]string, bool) {
+	var members []string
+	start, params, depth := 0, -1, 0
+	member := func(end int) {
+		if params >= 0 {
+			end = params
+		}
+		members = append(members, strings.Trim(v[start:end], " \t"))
+	}
+	quoted := false
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case c == ';' && params < 0:
+			params = i
+		case c == ',':
+			member(i)
+			start, params = i+1, -1
+		}
+	}
+	if quoted || depth > 0 {
+		return nil, false
+	}
+	member(len(v))
+	return members, true
+}
+
+// validSFKey reports whether s is an RFC 8941 dictionary key.
+func validSFKey(s string) bool {
+	if s == "" || !(isLowerAlpha(s, 0) || s[0] == '*') {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case c == '_', c == '-', c == '.', c == '*':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parseSFByteSequence parses an RFC 8941 byte sequence (":" base64 ":").
+// As RFC 8941 recommends, missing "=" padding and non-zero pad bits
+// are accepted.
+func parseSFByteSequence(s string) ([]byte, bool) {
+	if len(s) < 2 || s[0] != ':' || s[len(s)-1] != ':' {
+		return nil, false
+	}
+	s = s[1 : len(s)-1]
+	enc := base64.StdEncoding
+	if len(s)%4 != 0 {
+		enc = base64.RawStdEncoding
+	}
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
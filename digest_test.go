@@ -0,0 +1,175 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Examples from RFC 9530, Appendix B.
+const (
+	digestContent = `{"hello": "world"}`
+	digestSHA256  = "X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE="
+	digestSHA512  = "WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew=="
+)
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParseDigests(t *testing.T) {
+	sha256 := mustDecodeBase64(t, digestSHA256)
+	sha512 := mustDecodeBase64(t, digestSHA512)
+	tests := []struct {
+		in   string
+		want []Digest
+		err  bool
+	}{
+		{in: "", want: nil},
+		{in: "sha-256=:" + digestSHA256 + ":", want: []Digest{{"sha-256", sha256}}},
+		{
+			in:   "sha-256=:" + digestSHA256 + ":, sha-512=:" + digestSHA512 + ":",
+			want: []Digest{{"sha-256", sha256}, {"sha-512", sha512}},
+		},
+		{in: "sha-256=:" + strings.TrimRight(digestSHA256, "=") + ":", want: []Digest{{"sha-256", sha256}}},
+		{in: "sha-256=:" + digestSHA256 + ":;p=1", want: []Digest{{"sha-256", sha256}}},
+		{in: "unixsum=:AAA=:", want: []Digest{{"unixsum", []byte{0, 0}}}},
+		{in: "sha-256=:AAAA:, sha-512=:" + digestSHA512 + ":, sha-256=:" + digestSHA256 + ":", want: []Digest{{"sha-256", sha256}, {"sha-512", sha512}}},
+		{in: "sha-256=::", want: []Digest{{"sha-256", []byte{}}}},
+		{
+			in:   "sha-256=:" + digestSHA256 + `:;note="a,b;c", sha-512=:` + digestSHA512 + `:;x="\",y"`,
+			want: []Digest{{"sha-256", sha256}, {"sha-512", sha512}},
+		},
+
+		{in: "sha-256", err: true},
+		{in: "sha-256=" + digestSHA256, err: true},
+		{in: "sha-256=:" + digestSHA256, err: true},
+		{in: "sha-256=:not base64!:", err: true},
+		{in: "SHA-256=:" + digestSHA256 + ":", err: true},
+		{in: "=:" + digestSHA256 + ":", err: true},
+		{in: "sha-256=:" + digestSHA256 + ":,", err: true},
+		{in: "sha-256=:" + digestSHA256 + ": x", err: true},
+		{in: "sha-256=:" + digestSHA256 + `:;note="a,b`, err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDigests(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseDigests(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDigests(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseDigests(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDigestsMany(t *testing.T) {
+	// Replacing repeated algorithms must stay linear for large headers.
+	const n = 60000
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "a%d=:AAAA:, ", i)
+	}
+	b.WriteString("a0=::")
+	digests, err := ParseDigests(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digests) != n || len(digests[0].Value) != 0 {
+		t.Errorf("ParseDigests returned %d digests, first %v; want %d, first empty", len(digests), digests[0], n)
+	}
+}
+
+func TestFormatDigests(t *testing.T) {
+	digests := []Digest{
+		{"sha-256", mustDecodeBase64(t, digestSHA256)},
+		{"sha-512", mustDecodeBase64(t, digestSHA512)},
+	}
+	got, err := FormatDigests(digests...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "sha-256=:" + digestSHA256 + ":, sha-512=:" + digestSHA512 + ":"
+	if got != want {
+		t.Errorf("FormatDigests = %q, want %q", got, want)
+	}
+	back, err := ParseDigests(got)
+	if err != nil || !reflect.DeepEqual(back, digests) {
+		t.Errorf("ParseDigests(%q) = %v, %v; want %v", got, back, err, digests)
+	}
+
+	for _, alg := range []string{"", "SHA-256", "sha 256", "sha,256", "1sha"} {
+		if s, err := FormatDigests(Digest{Algorithm: alg}); err == nil {
+			t.Errorf("FormatDigests with algorithm %q = %q, want error", alg, s)
+		}
+	}
+}
+
+func TestComputeDigests(t *testing.T) {
+	got, err := ComputeDigests(strings.NewReader(digestContent), "sha-512", "sha-256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Digest{
+		{"sha-512", mustDecodeBase64(t, digestSHA512)},
+		{"sha-256", mustDecodeBase64(t, digestSHA256)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeDigests = %v, want %v", got, want)
+	}
+
+	if _, err := ComputeDigests(strings.NewReader(digestContent), "md5"); err == nil {
+		t.Error("ComputeDigests with md5 succeeded, want error")
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := ComputeDigests(&errReader{readErr}, "sha-256"); err != readErr {
+		t.Errorf("ComputeDigests with failing reader = %v, want %v", err, readErr)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestVerifyDigests(t *testing.T) {
+	sha256 := mustDecodeBase64(t, digestSHA256)
+	sha512 := mustDecodeBase64(t, digestSHA512)
+	tests := []struct {
+		content string
+		digests []Digest
+		want    error
+	}{
+		{digestContent, []Digest{{"sha-256", sha256}}, nil},
+		{digestContent, []Digest{{"sha-256", sha256}, {"sha-512", sha512}}, nil},
+		{digestContent, []Digest{{"unixsum", []byte{1}}, {"sha-512", sha512}}, nil},
+		{digestContent + " ", []Digest{{"sha-256", sha256}}, ErrDigestMismatch},
+		{digestContent, []Digest{{"sha-256", sha256}, {"sha-512", sha256}}, ErrDigestMismatch},
+		{digestContent, []Digest{{"sha-256", sha256[:4]}}, ErrDigestMismatch},
+		{digestContent, []Digest{{"unixsum", []byte{1}}}, ErrNoSupportedDigest},
+		{digestContent, nil, ErrNoSupportedDigest},
+	}
+	for i, tt := range tests {
+		if err := VerifyDigests(strings.NewReader(tt.content), tt.digests); err != tt.want {
+			t.Errorf("%d. VerifyDigests = %v, want %v", i, err, tt.want)
+		}
+	}
+}
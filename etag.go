@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+)
+
+// ComputeETag reads r to EOF and returns an entity-tag for its content,
+// suitable for the ETag header field of a generated response. The tag
+// is derived from a SHA-256 hash of the content, so equal content always
+// yields the same tag. If weak is true, the tag is marked as a weak
+// validator, for content that is semantically but not byte-for-byte
+// equivalent across responses, such as differently compressed output.
+func ComputeETag(r io.Reader, weak bool) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	// The URL-safe base64 alphabet holds only etagc characters.
+	tag := `"` + base64.RawURLEncoding.EncodeToString(h.Sum(nil)) + `"`
+	if weak {
+		tag = "W/" + tag
+	}
+	return tag, nil
+}
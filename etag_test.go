@@ -0,0 +1,38 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestComputeETag(t *testing.T) {
+	tests := []struct {
+		content string
+		weak    bool
+		want    string
+	}{
+		{"", false, `"47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU"`},
+		{"", true, `W/"47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU"`},
+		{digestContent, false, `"X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE"`},
+	}
+	for _, tt := range tests {
+		got, err := ComputeETag(strings.NewReader(tt.content), tt.weak)
+		if err != nil {
+			t.Errorf("ComputeETag(%q, %v): %v", tt.content, tt.weak, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ComputeETag(%q, %v) = %s, want %s", tt.content, tt.weak, got, tt.want)
+		}
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := ComputeETag(&errReader{readErr}, false); err != readErr {
+		t.Errorf("ComputeETag with failing reader = %v, want %v", err, readErr)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// This file implements the Prefer and Preference-Applied header fields
+// defined by RFC 7240.
+
+var errBadPrefer = errors.New("http: malformed Prefer")
+
+// A Preference is a single preference from a Prefer header field,
+// such as "return=minimal" or "wait=10".
+type Preference struct {
+	Token  string            // lower case
+	Value  string            // unquoted; empty if absent or given as ""
+	Params map[string]string // keys are lower case; nil if none
+}
+
+// Preferences is a parsed Prefer header field, in the order the
+// preferences appeared.
+type Preferences []Preference
+
+// ParsePrefer parses the values of one or more Prefer header fields.
+// If a preference appears more than once, only the first instance is
+// kept, as RFC 7240 section 2 requires.
+func ParsePrefer(values ...string) (Preferences, error) {
+	var prefs Preferences
+	seen := make(map[string]struct{})
+	for _, v := range values {
+		p := preferParser{s: v}
+		for {
+			p.skipListSeparators()
+			if p.done() {
+				break
+			}
+			pref, ok := p.preference()
+			if !ok {
+				return nil, errBadPrefer
+			}
+			if _, dup := seen[pref.Token]; !dup {
+				seen[pref.Token] = struct{}{}
+				prefs = append(prefs, pref)
+			}
+		}
+	}
+	return prefs, nil
+}
+
+// Get returns the preference with the given token, if present.
+// The token is matched case-insensitively.
+func (p Preferences) Get(token string) (Preference, bool) {
+	for _, pref := range p {
+		if strings.EqualFold(pref.Token, token) {
+			return pref, true
+		}
+	}
+	return Preference{}, false
+}
+
+// Return returns the value of the "return" preference:
+// "minimal", "representation", or "" if absent.
+func (p Preferences) Return() string {
+	pref, _ := p.Get("return")
+	return strings.ToLower(pref.Value)
+}
+
+// RespondAsync reports whether the "respond-async" preference is present.
+func (p Preferences) RespondAsync() bool {
+	_, ok := p.Get("respond-async")
+	return ok
+}
+
+// Wait returns the duration requested by the "wait" preference.
+// It reports false if the preference is absent or is not valid
+// delta-seconds. Durations too large for a time.Duration are clamped.
+func (p Preferences) Wait() (time.Duration, bool) {
+	pref, ok := p.Get("wait")
+	if !ok || pref.Value == "" {
+		return 0, false
+	}
+	const maxSeconds = maxInt64 / int64(time.Second)
+	var n int64
+	for i := 0; i < len(pref.Value); i++ {
+		c := pref.Value[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n <= maxSeconds {
+			n = n*10 + int64(c-'0')
+		}
+	}
+	if n > maxSeconds {
+		return maxInt64, true
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// Handling returns the value of the "handling" preference:
+// "strict", "lenient", or "" if absent.
+func (p Preferences) Handling() string {
+	pref, _ := p.Get("handling")
+	return strings.ToLower(pref.Value)
+}
+
+// FormatPreferenceApplied returns a Preference-Applied header field value
+// naming the given preferences. Parameters are not included, since
+// RFC 7240 section 3 does not allow them, and an empty Value is omitted.
+// It returns an error if a Token is not a valid token, or if a Value
+// holds control characters that a quoted-string cannot carry.
+func FormatPreferenceApplied(prefs ...Preference) (string, error) {
+	var b strings.Builder
+	for i, pref := range prefs {
+		if !isToken(pref.Token) {
+			return "", errBadPrefer
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(pref.Token)
+		if pref.Value != "" {
+			b.WriteByte('=')
+			if !writeWord(&b, pref.Value) {
+				return "", errBadPrefer
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// writeWord writes s as a token, or as a quoted-string if it is not one.
+// It reports false, leaving a partial result in b, if s holds a byte
+// that a quoted-string cannot carry.
+func writeWord(b *strings.Builder, s string) bool {
+	if isToken(s) {
+		b.WriteString(s)
+		return true
+	}
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isQuotedStringByte(c) {
+			return false
+		}
+		if c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('"')
+	return true
+}
+
+// isQuotedStringByte reports whether c may appear in a quoted-string,
+// either directly or in a quoted-pair: any byte except the control
+// characters other than HTAB.
+func isQuotedStringByte(c byte) bool {
+	return c == '\t' || c >= 0x20 && c != 0x7f
+}
+
+// preferParser parses the Prefer grammar:
+//
+//	preference = token [ BWS "=" BWS word ]
+//	             *( OWS ";" [ OWS parameter ] )
+//	parameter  = token [ BWS "=" BWS word ]
+type preferParser struct {
+	s string
+	i int
+}
+
+func (p *preferParser) done() bool { return p.i >= len(p.s) }
+
+func (p *preferParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+func (p *preferParser) skipSpace() {
+	for !p.done() && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *preferParser) skipListSeparators() {
+	for !p.done() && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == ',') {
+		p.i++
+	}
+}
+
+func (p *preferParser) preference() (Preference, bool) {
+	var pref Preference
+	var ok bool
+	if pref.Token, pref.Value, ok = p.param(); !ok {
+		return pref, false
+	}
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case 0, ',':
+			return pref, true
+		case ';':
+			p.i++
+			p.skipSpace()
+			if c := p.peek(); c == 0 || c == ',' || c == ';' {
+				continue
+			}
+			k, v, ok := p.param()
+			if !ok {
+				return pref, false
+			}
+			if pref.Params == nil {
+				pref.Params = make(map[string]string)
+			}
+			if _, dup := pref.Params[k]; !dup {
+				pref.Params[k] = v
+			}
+		default:
+			return pref, false
+		}
+	}
+}
+
+// param parses token [ BWS "=" BWS word ], returning the lower-cased token.
+func (p *preferParser) param() (key, value string, ok bool) {
+	if key = p.token(); key == "" {
+		return "", "", false
+	}
+	key = strings.ToLower(key)
+	p.skipSpace()
+	if p.peek() != '=' {
+		return key, "", true
+	}
+	p.i++
+	p.skipSpace()
+	if p.peek() == '"' {
+		value, ok = p.quotedString()
+		return key, value, ok
+	}
+	if value = p.token(); value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func (p *preferParser) token() string {
+	start := p.i
+	for !p.done() && isTokenChar(p.s[p.i]) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *preferParser) quotedString() (string, bool) {
+	var b strings.Builder
+	for p.i++; !p.done(); p.i++ {
+		switch c := p.s[p.i]; c {
+		case '"':
+			p.i++
+			return b.String(), true
+		case '\\':
+			if p.i+1 >= len(p.s) || !isQuotedStringByte(p.s[p.i+1]) {
+				return "", false
+			}
+			p.i++
+			b.WriteByte(p.s[p.i])
+		default:
+			if !isQuotedStringByte(c) {
+				return "", false
+			}
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
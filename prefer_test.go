@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePrefer(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want Preferences
+		err  bool
+	}{
+		{in: nil, want: nil},
+		{in: []string{""}, want: nil},
+		{in: []string{" , ,"}, want: nil},
+		{
+			in:   []string{"respond-async, wait=10"},
+			want: Preferences{{Token: "respond-async"}, {Token: "wait", Value: "10"}},
+		},
+		{
+			in:   []string{"Return = Minimal"},
+			want: Preferences{{Token: "return", Value: "Minimal"}},
+		},
+		{
+			in:   []string{`foo="a, b; \"c\" \\ d"`},
+			want: Preferences{{Token: "foo", Value: `a, b; "c" \ d`}},
+		},
+		{
+			in:   []string{`a=""`},
+			want: Preferences{{Token: "a", Value: ""}},
+		},
+		{
+			in: []string{`return=minimal; Foo="x;y" ; bar ;; ,wait=1`},
+			want: Preferences{
+				{Token: "return", Value: "minimal", Params: map[string]string{"foo": "x;y", "bar": ""}},
+				{Token: "wait", Value: "1"},
+			},
+		},
+		{
+			in:   []string{"a;p=1;p=2"},
+			want: Preferences{{Token: "a", Params: map[string]string{"p": "1"}}},
+		},
+
+		// The first instance of a preference wins, also across field values.
+		{
+			in:   []string{"wait=10, WAIT=20", "wait=30, handling=strict"},
+			want: Preferences{{Token: "wait", Value: "10"}, {Token: "handling", Value: "strict"}},
+		},
+		{
+			in:   []string{"return=minimal", "return=representation"},
+			want: Preferences{{Token: "return", Value: "minimal"}},
+		},
+
+		{in: []string{`a="unterminated`}, err: true},
+		{in: []string{`a="trailing\`}, err: true},
+		{in: []string{"a b"}, err: true},
+		{in: []string{"a=;"}, err: true},
+		{in: []string{"a="}, err: true},
+		{in: []string{"=x"}, err: true},
+		{in: []string{"a;=x"}, err: true},
+		{in: []string{"a;p=1 q"}, err: true},
+		{in: []string{`a="x"y`}, err: true},
+		{in: []string{"a=\"\x01\""}, err: true},
+		{in: []string{"ok", "a b"}, err: true},
+		{in: []string{"a=\"x\r\nb\""}, err: true},
+		{in: []string{"a=\"x\\\r\""}, err: true},
+		{in: []string{"a=\"x\\\x7f\""}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrefer(tt.in...)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParsePrefer(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePrefer(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePrefer(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPreferencesAccessors(t *testing.T) {
+	p, err := ParsePrefer("respond-async, return=Representation", "handling=LENIENT, wait=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.RespondAsync() {
+		t.Error("RespondAsync() = false, want true")
+	}
+	if got := p.Return(); got != "representation" {
+		t.Errorf("Return() = %q, want representation", got)
+	}
+	if got := p.Handling(); got != "lenient" {
+		t.Errorf("Handling() = %q, want lenient", got)
+	}
+	if pref, ok := p.Get("RETURN"); !ok || pref.Value != "Representation" {
+		t.Errorf("Get(RETURN) = %v, %v", pref, ok)
+	}
+
+	var empty Preferences
+	if empty.RespondAsync() || empty.Return() != "" || empty.Handling() != "" {
+		t.Error("accessors on empty Preferences returned values")
+	}
+}
+
+func TestPreferencesWait(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"wait=0", 0, true},
+		{"wait=10", 10 * time.Second, true},
+		{`wait="7"`, 7 * time.Second, true},
+		{"wait=9223372036", 9223372036 * time.Second, true},
+		{"wait=9223372037", maxInt64, true},
+		{"wait=99999999999999999999", maxInt64, true},
+		{"respond-async", 0, false},
+		{"wait", 0, false},
+		{"wait=+1", 0, false},
+		{"wait=-1", 0, false},
+		{"wait=1.5", 0, false},
+		{"wait=1s", 0, false},
+	}
+	for _, tt := range tests {
+		p, err := ParsePrefer(tt.in)
+		if err != nil {
+			t.Errorf("ParsePrefer(%q): %v", tt.in, err)
+			continue
+		}
+		got, ok := p.Wait()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePrefer(%q).Wait() = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatPreferenceApplied(t *testing.T) {
+	tests := []struct {
+		in   []Preference
+		want string
+		err  bool
+	}{
+		{in: nil, want: ""},
+		{in: []Preference{{Token: "respond-async"}}, want: "respond-async"},
+		{
+			in:   []Preference{{Token: "return", Value: "minimal", Params: map[string]string{"p": "1"}}, {Token: "wait", Value: "10"}},
+			want: "return=minimal, wait=10",
+		},
+		{in: []Preference{{Token: "x", Value: `a "b" \c`}}, want: `x="a \"b\" \\c"`},
+		{in: []Preference{{Token: "x", Value: "a,b"}}, want: `x="a,b"`},
+		{in: []Preference{{Token: "x", Value: "a\tb\x80"}}, want: "x=\"a\tb\x80\""},
+		{in: []Preference{{Token: ""}}, err: true},
+		{in: []Preference{{Token: "a b"}}, err: true},
+		{in: []Preference{{Token: "ok"}, {Token: "a=b"}}, err: true},
+		{in: []Preference{{Token: "return", Value: "x\r\nSet-Cookie: a=b"}}, err: true},
+		{in: []Preference{{Token: "x", Value: "a\nb"}}, err: true},
+		{in: []Preference{{Token: "x", Value: "a\x00"}}, err: true},
+		{in: []Preference{{Token: "x", Value: "a\x7f"}}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := FormatPreferenceApplied(tt.in...)
+		if tt.err {
+			if err == nil {
+				t.Errorf("FormatPreferenceApplied(%v) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FormatPreferenceApplied(%v): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatPreferenceApplied(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		// The output parses back to the same tokens and values.
+		back, err := ParsePrefer(got)
+		if err != nil {
+			t.Errorf("ParsePrefer(%q): %v", got, err)
+			continue
+		}
+		for i, pref := range back {
+			if pref.Token != tt.in[i].Token || pref.Value != tt.in[i].Value {
+				t.Errorf("ParsePrefer(%q)[%d] = %v, want token %q value %q", got, i, pref, tt.in[i].Token, tt.in[i].Value)
+			}
+		}
+	}
+}
+
+func TestParsePreferMany(t *testing.T) {
+	// Deduplication must stay linear for large (but legal) headers.
+	const n = 60000
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "p%d,", i)
+	}
+	b.WriteString("P0")
+	p, err := ParsePrefer(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != n {
+		t.Errorf("len(ParsePrefer(...)) = %d, want %d", len(p), n)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// ProblemJSONContentType is the media type of a JSON problem details
+// object, as defined by RFC 9457.
+const ProblemJSONContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 9457 problem details object.
+//
+// Its JSON encoding holds the standard members, omitting those that are
+// zero, followed by the extension members in sorted order.
+type ProblemDetails struct {
+	Type     string // URI reference identifying the problem type; "" means "about:blank"
+	Title    string // short, human-readable summary of the problem type
+	Status   int    // HTTP status code; 0 if absent
+	Detail   string // human-readable explanation of this occurrence
+	Instance string // URI reference identifying this occurrence
+
+	// Extensions holds any additional members. Its keys must not be
+	// the names of the standard members. When decoding, values are
+	// stored as encoding/json decodes them into an interface value.
+	Extensions map[string]any
+}
+
+var errProblemExtension = errors.New("http: problem details extension uses a standard member name")
+
+// MarshalJSON implements json.Marshaler.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	add := func(name string, v any) error {
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		key, _ := json.Marshal(name)
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+		return nil
+	}
+	std := p.standardMembers()
+	for _, m := range std {
+		var err error
+		if m.s != nil && *m.s != "" {
+			err = add(m.name, *m.s)
+		} else if m.n != nil && *m.n != 0 {
+			err = add(m.name, *m.n)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(p.Extensions))
+	for k := range p.Extensions {
+		if slices.ContainsFunc(std, func(m problemMember) bool { return m.name == k }) {
+			return nil, errProblemExtension
+		}
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if err := add(k, p.Extensions[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// As RFC 9457 section 3.1 requires, a standard member whose value has
+// the wrong type is ignored rather than treated as an error. As with
+// other types, a JSON null leaves p unchanged.
+func (p *ProblemDetails) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		return nil
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	*p = ProblemDetails{}
+	std := p.standardMembers()
+Members:
+	for k, raw := range members {
+		for _, m := range std {
+			if m.name != k {
+				continue
+			}
+			if m.s != nil {
+				var s string
+				if json.Unmarshal(raw, &s) == nil {
+					*m.s = s
+				}
+			} else {
+				var n int
+				if json.Unmarshal(raw, &n) == nil {
+					*m.n = n
+				}
+			}
+			continue Members
+		}
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[k] = v
+	}
+	return nil
+}
+
+// problemMember is a standard problem details member and the field
+// holding it; exactly one of s and n is set.
+type problemMember struct {
+	name string
+	s    *string
+	n    *int
+}
+
+func (p *ProblemDetails) standardMembers() []problemMember {
+	return []problemMember{
+		{name: "type", s: &p.Type},
+		{name: "title", s: &p.Title},
+		{name: "status", n: &p.Status},
+		{name: "detail", s: &p.Detail},
+		{name: "instance", s: &p.Instance},
+	}
+}
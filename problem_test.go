@@ -0,0 +1,134 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProblemDetailsMarshal(t *testing.T) {
+	tests := []struct {
+		in   ProblemDetails
+		want string
+		err  bool
+	}{
+		{in: ProblemDetails{}, want: `{}`},
+		{in: ProblemDetails{Status: 404, Title: "Not Found"}, want: `{"title":"Not Found","status":404}`},
+		{
+			in: ProblemDetails{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   403,
+				Detail:   "Your current balance is 30, but that costs 50.",
+				Instance: "/account/12345/msgs/abc",
+				Extensions: map[string]any{
+					"balance":  30,
+					"accounts": []string{"/account/12345", "/account/67890"},
+				},
+			},
+			want: `{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.",` +
+				`"status":403,"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc",` +
+				`"accounts":["/account/12345","/account/67890"],"balance":30}`,
+		},
+		{in: ProblemDetails{Detail: "<a & b>"}, want: `{"detail":"\u003ca \u0026 b\u003e"}`},
+		{in: ProblemDetails{Extensions: map[string]any{"status": 500}}, err: true},
+		{in: ProblemDetails{Extensions: map[string]any{"bad": make(chan int)}}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Marshal(%+v) = %s, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Marshal(%+v): %v", tt.in, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%+v) =\n%s\nwant\n%s", tt.in, got, tt.want)
+		}
+		// Pointers encode the same way.
+		if got2, err := json.Marshal(&tt.in); err != nil || string(got2) != tt.want {
+			t.Errorf("Marshal(&%+v) = %s, %v; want %s", tt.in, got2, err, tt.want)
+		}
+	}
+}
+
+func TestProblemDetailsUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ProblemDetails
+		err  bool
+	}{
+		{in: `{}`, want: ProblemDetails{}},
+		{
+			in: `{"type":"https://example.com/probs/out-of-credit","title":"Out of credit","status":403,` +
+				`"detail":"d","instance":"/i","balance":30,"accounts":["/a"],"nested":{"x":null}}`,
+			want: ProblemDetails{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "Out of credit",
+				Status:   403,
+				Detail:   "d",
+				Instance: "/i",
+				Extensions: map[string]any{
+					"balance":  float64(30),
+					"accounts": []any{"/a"},
+					"nested":   map[string]any{"x": nil},
+				},
+			},
+		},
+		// Standard members with the wrong type are ignored.
+		{in: `{"status":"404","title":7,"type":null,"detail":["x"],"instance":{}}`, want: ProblemDetails{}},
+		{in: `{"status":404.5,"title":"t"}`, want: ProblemDetails{Title: "t"}},
+		// Null is a no-op, as for other types.
+		{in: `null`, want: ProblemDetails{Title: "stale", Extensions: map[string]any{"stale": true}}},
+		{in: ` null `, want: ProblemDetails{Title: "stale", Extensions: map[string]any{"stale": true}}},
+
+		{in: `[]`, err: true},
+		{in: `"problem"`, err: true},
+		{in: `{"title":`, err: true},
+	}
+	for _, tt := range tests {
+		got := ProblemDetails{Title: "stale", Extensions: map[string]any{"stale": true}}
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %+v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestProblemDetailsRoundTrip(t *testing.T) {
+	p := ProblemDetails{
+		Type:       "about:blank",
+		Title:      "Too Many Requests",
+		Status:     429,
+		Extensions: map[string]any{"retry": "later", "limits": map[string]any{"hour": float64(100)}},
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ProblemDetails
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip of %s = %+v, want %+v", b, got, p)
+	}
+}
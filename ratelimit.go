@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBadRateLimit = errors.New("http: malformed RateLimit")
+
+// RateLimit holds the values of a RateLimit header field, as defined by
+// the IETF draft "RateLimit header fields for HTTP" (draft-ietf-httpapi-
+// ratelimit-headers-07), for example:
+//
+//	RateLimit: limit=100, remaining=50, reset=30
+type RateLimit struct {
+	Limit     int64         // quota units available in the current window
+	Remaining int64         // quota units remaining in the current window
+	Reset     time.Duration // time until the quota resets, in whole seconds
+}
+
+// ParseRateLimit parses a RateLimit header field value.
+// The limit, remaining, and reset members are required, and each must be
+// a non-negative Structured Field integer. As in any RFC 8941 dictionary,
+// a repeated member replaces earlier ones, and unknown members and
+// parameters are ignored.
+func ParseRateLimit(v string) (RateLimit, error) {
+	var rl RateLimit
+	var seen uint8
+	members, ok := splitSFDictionary(v)
+	if !ok {
+		return RateLimit{}, errBadRateLimit
+	}
+	for _, m := range members {
+		key, value, ok := strings.Cut(m, "=")
+		if !ok {
+			continue
+		}
+		var bit uint8
+		switch key {
+		case "limit":
+			bit = 1 << 0
+		case "remaining":
+			bit = 1 << 1
+		case "reset":
+			bit = 1 << 2
+		default:
+			continue
+		}
+		n, ok := parseSFDigits(value)
+		if !ok {
+			return RateLimit{}, errBadRateLimit
+		}
+		seen |= bit
+		switch key {
+		case "limit":
+			rl.Limit = n
+		case "remaining":
+			rl.Remaining = n
+		case "reset":
+			if n > maxInt64/int64(time.Second) {
+				rl.Reset = maxInt64
+			} else {
+				rl.Reset = time.Duration(n) * time.Second
+			}
+		}
+	}
+	if seen != 1<<0|1<<1|1<<2 {
+		return RateLimit{}, errBadRateLimit
+	}
+	return rl, nil
+}
+
+// maxSFInteger is the largest RFC 8941 integer.
+const maxSFInteger = 999999999999999
+
+// parseSFDigits parses a non-negative RFC 8941 integer:
+// one to fifteen ASCII digits, with no sign.
+func parseSFDigits(s string) (int64, bool) {
+	if len(s) == 0 || len(s) > 15 {
+		return 0, false
+	}
+	var n int64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	return n, true
+}
+
+// String returns rl formatted as a RateLimit header field value.
+// Values outside the range of a non-negative Structured Field integer
+// are clamped to it, so the result is always accepted by ParseRateLimit.
+// Reset is rounded up to a whole number of seconds so that clients do
+// not retry early.
+func (rl RateLimit) String() string {
+	reset := (rl.Reset + time.Second - 1) / time.Second
+	if rl.Reset > maxInt64-time.Second {
+		reset = rl.Reset / time.Second
+	}
+	return "limit=" + strconv.FormatInt(clampSFInteger(rl.Limit), 10) +
+		", remaining=" + strconv.FormatInt(clampSFInteger(rl.Remaining), 10) +
+		", reset=" + strconv.FormatInt(clampSFInteger(int64(reset)), 10)
+}
+
+// clampSFInteger clamps n to the range [0, maxSFInteger].
+func clampSFInteger(n int64) int64 {
+	return min(max(n, 0), maxSFInteger)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want RateLimit
+		err  bool
+	}{
+		{in: "limit=100, remaining=50, reset=30", want: RateLimit{100, 50, 30 * time.Second}},
+		{in: "reset=0,limit=0,remaining=0", want: RateLimit{}},
+		{in: "limit=10;w=60, remaining=5;q=1, reset=1", want: RateLimit{10, 5, time.Second}},
+		{in: `policy="default", limit=1, extra, remaining=2, reset=3, foo=?1`, want: RateLimit{1, 2, 3 * time.Second}},
+		{in: `limit=1;note="a, remaining=9", remaining=2, reset=3`, want: RateLimit{1, 2, 3 * time.Second}},
+		{in: `policy=("a, b" "c;d");w=1, limit=1, remaining=2, reset=3`, want: RateLimit{1, 2, 3 * time.Second}},
+		{in: "limit=1, limit=9, remaining=2, reset=3", want: RateLimit{9, 2, 3 * time.Second}},
+		{in: "limit=1,remaining=2,reset=999999999999999", want: RateLimit{1, 2, maxInt64}},
+
+		{in: "", err: true},
+		{in: "limit=1, remaining=2", err: true},
+		{in: "limit=1, reset=3", err: true},
+		{in: "remaining=2, reset=3", err: true},
+		{in: "limit=-1, remaining=2, reset=3", err: true},
+		{in: "limit=+1, remaining=2, reset=3", err: true},
+		{in: "limit=1, remaining=2, reset=-3", err: true},
+		{in: "limit=1.5, remaining=2, reset=3", err: true},
+		{in: "limit=x, remaining=2, reset=3", err: true},
+		{in: "limit=, remaining=2, reset=3", err: true},
+		{in: "limit=1000000000000000, remaining=2, reset=3", err: true},
+		{in: "Limit=1, remaining=2, reset=3", err: true},
+		{in: `limit=1, remaining=2, reset=3, policy="unterminated`, err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRateLimit(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseRateLimit(%q) = %+v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRateLimit(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRateLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitString(t *testing.T) {
+	tests := []struct {
+		in   RateLimit
+		want string
+	}{
+		{RateLimit{100, 50, 30 * time.Second}, "limit=100, remaining=50, reset=30"},
+		{RateLimit{}, "limit=0, remaining=0, reset=0"},
+		{RateLimit{10, 5, 1500 * time.Millisecond}, "limit=10, remaining=5, reset=2"},
+		{RateLimit{10, 5, time.Nanosecond}, "limit=10, remaining=5, reset=1"},
+		{RateLimit{-1, -5, -time.Second}, "limit=0, remaining=0, reset=0"},
+		{RateLimit{1, 1, maxInt64}, "limit=1, remaining=1, reset=9223372036"},
+		{RateLimit{1, 1, maxInt64 - time.Second/2}, "limit=1, remaining=1, reset=9223372036"},
+		{RateLimit{1 << 62, maxInt64, 0}, "limit=999999999999999, remaining=999999999999999, reset=0"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.in, got, tt.want)
+		}
+		// The output is always accepted by ParseRateLimit.
+		if _, err := ParseRateLimit(tt.want); err != nil {
+			t.Errorf("ParseRateLimit(%q): %v", tt.want, err)
+		}
+		if tt.in.Limit < 0 || tt.in.Limit > maxSFInteger || tt.in.Reset >= maxInt64-time.Second {
+			continue
+		}
+		// Whole-second values round-trip through ParseRateLimit.
+		if tt.in.Reset%time.Second == 0 {
+			got, err := ParseRateLimit(tt.want)
+			if err != nil || got != tt.in {
+				t.Errorf("ParseRateLimit(%q) = %+v, %v; want %+v", tt.want, got, err, tt.in)
+			}
+		}
+	}
+}
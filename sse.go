@@ -0,0 +1,206 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This file implements the framing of server-sent events, as defined by
+// the HTML Living Standard, section 9.2 "Server-sent events".
+
+// EventStreamContentType is the media type of a server-sent event stream.
+const EventStreamContentType = "text/event-stream"
+
+var errBadEvent = errors.New("http: invalid server-sent event field")
+
+// An Event is a single server-sent event.
+//
+// When read by an EventReader, ID and Retry hold the last event ID and
+// the reconnection time in effect when the event was dispatched, which
+// may have been set by earlier events.
+type Event struct {
+	ID    string        // event ID; "" if none
+	Type  string        // event type; "" means "message"
+	Data  string        // event data; lines are separated by "\n"
+	Retry time.Duration // reconnection time; 0 if not set
+}
+
+// WriteEvent writes e to w in the event stream format. Each line of
+// Data is written as its own data field, and Retry is written in whole
+// milliseconds, truncated, if it is positive. It returns an error if ID
+// or Type holds a line break, or ID holds a NUL, since a reader could
+// not recover them.
+func WriteEvent(w io.Writer, e Event) error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") || strings.ContainsAny(e.Type, "\r\n") {
+		return errBadEvent
+	}
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Type != "" {
+		b.WriteString("event: " + e.Type + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range splitEventLines(e.Data) {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteEventComment writes text to w as an event stream comment, which
+// readers ignore. Writing an empty comment periodically keeps idle
+// connections open.
+func WriteEventComment(w io.Writer, text string) error {
+	var b strings.Builder
+	for _, line := range splitEventLines(text) {
+		b.WriteByte(':')
+		if line != "" {
+			b.WriteString(" " + line)
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// splitEventLines splits s at each CRLF, LF, or CR.
+func splitEventLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.Split(s, "\n")
+}
+
+// An EventReader reads server-sent events from an event stream.
+type EventReader struct {
+	r      *bufio.Reader
+	lastID string
+	retry  time.Duration
+	bom    bool // the leading byte order mark has been checked for
+	skipLF bool // the previous line ended in CR
+}
+
+// NewEventReader returns an EventReader reading from r.
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next event in the stream. Blocks without data are
+// not events and are skipped. At the end of the stream, Next returns
+// io.EOF, discarding any incomplete event.
+func (r *EventReader) Next() (Event, error) {
+	if !r.bom {
+		r.bom = true
+		// Peek past the first byte only if it can start a BOM, so a
+		// short first line on a live stream is not held back.
+		if b, _ := r.r.Peek(1); len(b) == 1 && b[0] == 0xef {
+			if b, _ := r.r.Peek(3); string(b) == "\xef\xbb\xbf" {
+				r.r.Discard(3)
+			}
+		}
+	}
+	var typ string
+	var data strings.Builder
+	hasData := false
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+		if line == "" {
+			if !hasData {
+				typ = ""
+				continue
+			}
+			return Event{
+				ID:    r.lastID,
+				Type:  typ,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: r.retry,
+			}, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			typ = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.lastID = value
+			}
+		case "retry":
+			if d, ok := parseEventRetry(value); ok {
+				r.retry = d
+			}
+		}
+	}
+}
+
+// readLine reads a line ending in CRLF, LF, or CR, and returns it
+// without the line ending.
+func (r *EventReader) readLine() (string, error) {
+	var line []byte
+	for {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if r.skipLF {
+			r.skipLF = false
+			if c == '\n' {
+				continue
+			}
+		}
+		switch c {
+		case '\r':
+			r.skipLF = true
+			return string(line), nil
+		case '\n':
+			return string(line), nil
+		}
+		line = append(line, c)
+	}
+}
+
+// parseEventRetry parses the value of a retry field, which must consist
+// of ASCII digits only, as a number of milliseconds. Durations too large
+// for a time.Duration are clamped.
+func parseEventRetry(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	const maxMillis = maxInt64 / int64(time.Millisecond)
+	var n int64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n <= maxMillis {
+			n = n*10 + int64(c-'0')
+		}
+	}
+	if n > maxMillis {
+		return maxInt64, true
+	}
+	return time.Duration(n) * time.Millisecond, true
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteEvent(t *testing.T) {
+	tests := []struct {
+		in   Event
+		want string
+		err  bool
+	}{
+		{in: Event{}, want: "data: \n\n"},
+		{in: Event{Data: "hello"}, want: "data: hello\n\n"},
+		{
+			in:   Event{ID: "7", Type: "update", Data: "a\nb\r\nc\rd", Retry: 1500*time.Millisecond + 1},
+			want: "id: 7\nevent: update\nretry: 1500\ndata: a\ndata: b\ndata: c\ndata: d\n\n",
+		},
+		{in: Event{Data: " x:y\n"}, want: "data:  x:y\ndata: \n\n"},
+		{in: Event{Data: "x", Retry: -time.Second}, want: "data: x\n\n"},
+		{in: Event{ID: "a\nb"}, err: true},
+		{in: Event{ID: "a\rb"}, err: true},
+		{in: Event{ID: "a\x00b"}, err: true},
+		{in: Event{Type: "a\nid: 1"}, err: true},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		err := WriteEvent(&b, tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("WriteEvent(%+v) wrote %q, want error", tt.in, b.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("WriteEvent(%+v): %v", tt.in, err)
+			continue
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("WriteEvent(%+v) wrote %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	writeErr := errors.New("write failed")
+	if err := WriteEvent(errWriter{writeErr}, Event{Data: "x"}); err != writeErr {
+		t.Errorf("WriteEvent with failing writer = %v, want %v", err, writeErr)
+	}
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestWriteEventComment(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ":\n"},
+		{"keep-alive", ": keep-alive\n"},
+		{"a\r\nb", ": a\n: b\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteEventComment(&b, tt.in); err != nil {
+			t.Errorf("WriteEventComment(%q): %v", tt.in, err)
+			continue
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("WriteEventComment(%q) wrote %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEventReader(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Event
+	}{
+		{in: "", want: nil},
+		{in: "data: hello\n\n", want: []Event{{Data: "hello"}}},
+		{in: "\xef\xbb\xbfdata: bom\n\n", want: []Event{{Data: "bom"}}},
+		{in: "data:\n\n", want: []Event{{Data: ""}}},
+		{in: "data\n\n", want: []Event{{Data: ""}}},
+		{in: "data: a\ndata:b\ndata:  c\n\n", want: []Event{{Data: "a\nb\n c"}}},
+		{in: "data: a\r\ndata: b\rdata: c\n\r\n", want: []Event{{Data: "a\nb\nc"}}},
+
+		// Examples from the HTML Living Standard, section 9.2.4.
+		{
+			in: ": test stream\n\ndata: first event\nid: 1\n\ndata:second event\nid\n\ndata:  third event\n",
+			want: []Event{
+				{ID: "1", Data: "first event"},
+				{ID: "", Data: "second event"},
+			},
+		},
+		{
+			in:   "data\n\ndata\ndata\n\ndata:\n",
+			want: []Event{{Data: ""}, {Data: "\n"}},
+		},
+
+		// Type applies to one event; ID and Retry persist.
+		{
+			in: "event: add\nid: 5\nretry: 3000\ndata: x\n\ndata: y\n\nid: 6\nretry: soon\nevent: skipped\n\ndata: z\n\n",
+			want: []Event{
+				{ID: "5", Type: "add", Data: "x", Retry: 3 * time.Second},
+				{ID: "5", Data: "y", Retry: 3 * time.Second},
+				{ID: "6", Data: "z", Retry: 3 * time.Second},
+			},
+		},
+		{in: "id: a\x00b\ndata: x\n\n", want: []Event{{Data: "x"}}},
+		{in: "retry: 99999999999999999999\ndata: x\n\n", want: []Event{{Data: "x", Retry: maxInt64}}},
+		{in: "retry: -1\ndata: x\n\n", want: []Event{{Data: "x"}}},
+		{in: "Data: x\nfoo: bar\n\n", want: nil},
+	}
+	for _, tt := range tests {
+		r := NewEventReader(strings.NewReader(tt.in))
+		var got []Event
+		for {
+			e, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Next on %q: %v", tt.in, err)
+			}
+			got = append(got, e)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("events of %q = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	events := []Event{
+		{ID: "1", Type: "update", Data: "line one\nline two", Retry: 2 * time.Second},
+		{ID: "2", Data: " leading space"},
+		{ID: "3", Data: ""},
+	}
+	var b strings.Builder
+	for _, e := range events {
+		if err := WriteEvent(&b, e); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteEventComment(&b, "keep-alive"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewEventReader(strings.NewReader(b.String()))
+	for i, want := range events {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next %d: %v", i, err)
+		}
+		want.Retry = 2 * time.Second
+		if got != want {
+			t.Errorf("Next %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next at end = %v, want io.EOF", err)
+	}
+}
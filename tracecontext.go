@@ -0,0 +1,441 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// This file implements parsing and formatting of the W3C Trace Context
+// (https://www.w3.org/TR/trace-context/) traceparent and tracestate
+// header values, and of the W3C Baggage (https://www.w3.org/TR/baggage/)
+// header value, and context helpers for carrying them from an inbound
+// request to the outbound requests made on its behalf.
+
+var (
+	errBadTraceParent = errors.New("http: malformed traceparent")
+	errBadTraceState  = errors.New("http: malformed tracestate")
+	errBadBaggage     = errors.New("http: malformed baggage")
+)
+
+// TraceParent is a parsed traceparent header value.
+type TraceParent struct {
+	Version  byte
+	TraceID  [16]byte
+	ParentID [8]byte
+	Flags    byte
+}
+
+// traceFlagSampled is the sampled bit of TraceParent.Flags.
+const traceFlagSampled = 0x01
+
+// ParseTraceParent parses a traceparent header value.
+//
+// Values with a version newer than 00 are accepted as long as they
+// begin with a valid version 00 prefix; any trailing fields are ignored,
+// as the specification requires.
+func ParseTraceParent(v string) (TraceParent, error) {
+	var tp TraceParent
+	v = strings.TrimSpace(v)
+	const size = len("00-") + 32 + len("-") + 16 + len("-") + 2
+	if len(v) < size || v[2] != '-' || v[35] != '-' || v[52] != '-' {
+		return tp, errBadTraceParent
+	}
+	var ver [1]byte
+	if !decodeLowerHex(ver[:], v[0:2]) || ver[0] == 0xff {
+		return tp, errBadTraceParent
+	}
+	tp.Version = ver[0]
+	if len(v) > size && (tp.Version == 0 || v[size] != '-') {
+		return tp, errBadTraceParent
+	}
+	var flags [1]byte
+	if !decodeLowerHex(tp.TraceID[:], v[3:35]) ||
+		!decodeLowerHex(tp.ParentID[:], v[36:52]) ||
+		!decodeLowerHex(flags[:], v[53:55]) {
+		return tp, errBadTraceParent
+	}
+	tp.Flags = flags[0]
+	if tp.TraceID == [16]byte{} || tp.ParentID == [8]byte{} {
+		return tp, errBadTraceParent
+	}
+	return tp, nil
+}
+
+// Sampled reports whether the sampled flag is set in tp.
+func (tp TraceParent) Sampled() bool { return tp.Flags&traceFlagSampled != 0 }
+
+// String returns tp formatted as a traceparent header value.
+// Only the fields defined by version 00 are included.
+func (tp TraceParent) String() string {
+	var b strings.Builder
+	b.Grow(55)
+	b.WriteString(hex.EncodeToString([]byte{tp.Version}))
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString(tp.TraceID[:]))
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString(tp.ParentID[:]))
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString([]byte{tp.Flags}))
+	return b.String()
+}
+
+// decodeLowerHex decodes s into dst, reporting whether s is exactly
+// len(dst)*2 lowercase hex digits.
+func decodeLowerHex(dst []byte, s string) bool {
+	if len(s) != len(dst)*2 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// maxTraceStateMembers is the maximum number of list members in a
+// tracestate value.
+const maxTraceStateMembers = 32
+
+// TraceState is a parsed tracestate header value: an ordered list of
+// vendor-specific key/value pairs, most recently updated first.
+// The zero value is an empty TraceState.
+type TraceState struct {
+	list []traceStateMember
+}
+
+type traceStateMember struct {
+	key, value string
+}
+
+// ParseTraceState parses a tracestate header value.
+// Empty list members are ignored.
+func ParseTraceState(v string) (TraceState, error) {
+	var ts TraceState
+	for _, m := range strings.Split(v, ",") {
+		m = strings.Trim(m, " \t")
+		if m == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(m, "=")
+		if !ok || !validTraceStateKey(key) || !validTraceStateValue(value) {
+			return TraceState{}, errBadTraceState
+		}
+		if len(ts.list) == maxTraceStateMembers || ts.index(key) >= 0 {
+			return TraceState{}, errBadTraceState
+		}
+		ts.list = append(ts.list, traceStateMember{key, value})
+	}
+	return ts, nil
+}
+
+// Len returns the number of list members in ts.
+func (ts TraceState) Len() int { return len(ts.list) }
+
+// Get returns the value associated with key, or "" if there is none.
+func (ts TraceState) Get(key string) string {
+	if i := ts.index(key); i >= 0 {
+		return ts.list[i].value
+	}
+	return ""
+}
+
+// Set returns a copy of ts with key set to value and moved to the front
+// of the list, as a vendor does when it updates its own entry.
+// If the list is full, the last member is dropped.
+func (ts TraceState) Set(key, value string) (TraceState, error) {
+	if !validTraceStateKey(key) || !validTraceStateValue(value) {
+		return ts, errBadTraceState
+	}
+	list := make([]traceStateMember, 0, len(ts.list)+1)
+	list = append(list, traceStateMember{key, value})
+	for _, m := range ts.list {
+		if m.key != key {
+			list = append(list, m)
+		}
+	}
+	if len(list) > maxTraceStateMembers {
+		list = list[:maxTraceStateMembers]
+	}
+	return TraceState{list: list}, nil
+}
+
+// Delete returns a copy of ts without key.
+func (ts TraceState) Delete(key string) TraceState {
+	i := ts.index(key)
+	if i < 0 {
+		return ts
+	}
+	list := make([]traceStateMember, 0, len(ts.list)-1)
+	list = append(list, ts.list[:i]...)
+	list = append(list, ts.list[i+1:]...)
+	return TraceState{list: list}
+}
+
+// String returns ts formatted as a tracestate header value.
+func (ts TraceState) String() string {
+	var b strings.Builder
+	for i, m := range ts.list {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.key)
+		b.WriteByte('=')
+		b.WriteString(m.value)
+	}
+	return b.String()
+}
+
+func (ts TraceState) index(key string) int {
+	for i, m := range ts.list {
+		if m.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// validTraceStateKey reports whether key is a simple-key or a
+// multi-tenant key (tenant-id "@" system-id).
+func validTraceStateKey(key string) bool {
+	tenant, system, multi := strings.Cut(key, "@")
+	if !multi {
+		return len(key) <= 256 && isLowerAlpha(key, 0) && validTraceStateKeyChars(key)
+	}
+	return len(tenant) >= 1 && len(tenant) <= 241 &&
+		(isLowerAlpha(tenant, 0) || '0' <= tenant[0] && tenant[0] <= '9') &&
+		validTraceStateKeyChars(tenant) &&
+		len(system) >= 1 && len(system) <= 14 &&
+		isLowerAlpha(system, 0) && validTraceStateKeyChars(system)
+}
+
+func validTraceStateKeyChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case c == '_', c == '-', c == '*', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validTraceStateValue reports whether v is 1 to 256 printable ASCII
+// characters other than ',' and '=', not ending in a space.
+func validTraceStateValue(v string) bool {
+	if len(v) == 0 || len(v) > 256 || v[len(v)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if c := v[i]; c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxBaggageSize is the maximum size in bytes of a baggage value.
+const maxBaggageSize = 8192
+
+// BaggageMember is a single list member of a baggage header value.
+type BaggageMember struct {
+	Key   string
+	Value string // decoded
+
+	// Properties holds the member's metadata, each either a key or a
+	// key=value pair with surrounding whitespace removed.
+	// Property values are not percent-decoded.
+	Properties []string
+}
+
+// ParseBaggage parses a baggage header value.
+// Member values are percent-decoded.
+func ParseBaggage(v string) ([]BaggageMember, error) {
+	if len(v) > maxBaggageSize {
+		return nil, errBadBaggage
+	}
+	var members []BaggageMember
+	for _, m := range strings.Split(v, ",") {
+		m = strings.Trim(m, " \t")
+		if m == "" {
+			continue
+		}
+		fields := strings.Split(m, ";")
+		key, value, ok := strings.Cut(fields[0], "=")
+		key = strings.Trim(key, " \t")
+		value = strings.Trim(value, " \t")
+		if !ok || !isToken(key) {
+			return nil, errBadBaggage
+		}
+		value, ok = unescapeBaggage(value)
+		if !ok {
+			return nil, errBadBaggage
+		}
+		bm := BaggageMember{Key: key, Value: value}
+		for _, p := range fields[1:] {
+			p, ok := cleanBaggageProperty(p)
+			if !ok {
+				return nil, errBadBaggage
+			}
+			bm.Properties = append(bm.Properties, p)
+		}
+		members = append(members, bm)
+	}
+	return members, nil
+}
+
+// FormatBaggage returns members formatted as a baggage header value.
+// Member values are percent-encoded as needed. It returns an error if
+// a key or property is malformed or the result exceeds the size limit
+// that ParseBaggage enforces.
+func FormatBaggage(members []BaggageMember) (string, error) {
+	var b strings.Builder
+	for i, m := range members {
+		if !isToken(m.Key) {
+			return "", errBadBaggage
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.Key)
+		b.WriteByte('=')
+		escapeBaggage(&b, m.Value)
+		for _, p := range m.Properties {
+			p, ok := cleanBaggageProperty(p)
+			if !ok {
+				return "", errBadBaggage
+			}
+			b.WriteByte(';')
+			b.WriteString(p)
+		}
+	}
+	if b.Len() > maxBaggageSize {
+		return "", errBadBaggage
+	}
+	return b.String(), nil
+}
+
+// cleanBaggageProperty validates a baggage property (key, or key=value)
+// and returns it with optional whitespace removed.
+func cleanBaggageProperty(p string) (string, bool) {
+	key, value, hasValue := strings.Cut(p, "=")
+	key = strings.Trim(key, " \t")
+	if !isToken(key) {
+		return "", false
+	}
+	if !hasValue {
+		return key, true
+	}
+	value = strings.Trim(value, " \t")
+	if _, ok := unescapeBaggage(value); !ok {
+		return "", false
+	}
+	return key + "=" + value, true
+}
+
+// isBaggageOctet reports whether c may appear unescaped in a baggage value.
+func isBaggageOctet(c byte) bool {
+	return c == 0x21 || 0x23 <= c && c <= 0x2b || 0x2d <= c && c <= 0x3a ||
+		0x3c <= c && c <= 0x5b || 0x5d <= c && c <= 0x7e
+}
+
+func escapeBaggage(b *strings.Builder, s string) {
+	const upperhex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isBaggageOctet(c) && c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(upperhex[c>>4])
+		b.WriteByte(upperhex[c&15])
+	}
+}
+
+func unescapeBaggage(s string) (string, bool) {
+	if !strings.Contains(s, "%") {
+		for i := 0; i < len(s); i++ {
+			if !isBaggageOctet(s[i]) {
+				return "", false
+			}
+		}
+		return s, true
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' {
+			if i+3 > len(s) {
+				return "", false
+			}
+			var d [1]byte
+			if _, err := hex.Decode(d[:], []byte(s[i+1:i+3])); err != nil {
+				return "", false
+			}
+			b = append(b, d[0])
+			i += 2
+			continue
+		}
+		if !isBaggageOctet(c) {
+			return "", false
+		}
+		b = append(b, c)
+	}
+	return string(b), true
+}
+
+var (
+	traceParentContextKey = &contextKey{"trace-parent"}
+	traceStateContextKey  = &contextKey{"trace-state"}
+	baggageContextKey     = &contextKey{"baggage"}
+)
+
+// ContextWithTraceParent returns a copy of ctx carrying tp.
+func ContextWithTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentContextKey, tp)
+}
+
+// TraceParentFromContext returns the TraceParent carried by ctx, if any.
+func TraceParentFromContext(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentContextKey).(TraceParent)
+	return tp, ok
+}
+
+// ContextWithTraceState returns a copy of ctx carrying ts.
+func ContextWithTraceState(ctx context.Context, ts TraceState) context.Context {
+	return context.WithValue(ctx, traceStateContextKey, ts)
+}
+
+// TraceStateFromContext returns the TraceState carried by ctx, if any.
+func TraceStateFromContext(ctx context.Context) (TraceState, bool) {
+	ts, ok := ctx.Value(traceStateContextKey).(TraceState)
+	return ts, ok
+}
+
+// ContextWithBaggage returns a copy of ctx carrying members.
+// The members and their properties are copied, so later changes to
+// members are not seen.
+func ContextWithBaggage(ctx context.Context, members []BaggageMember) context.Context {
+	members = slices.Clone(members)
+	for i := range members {
+		members[i].Properties = slices.Clone(members[i].Properties)
+	}
+	return context.WithValue(ctx, baggageContextKey, members)
+}
+
+// BaggageFromContext returns the baggage carried by ctx, if any.
+// The caller must not modify the returned slice.
+func BaggageFromContext(ctx context.Context) ([]BaggageMember, bool) {
+	members, ok := ctx.Value(baggageContextKey).([]BaggageMember)
+	return members, ok
+}
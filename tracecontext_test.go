@@ -0,0 +1,353 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID = "00f067aa0ba902b7"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	valid := "00-" + testTraceID + "-" + testParentID + "-01"
+	tests := []struct {
+		in      string
+		want    string // formatted result; "" means an error is expected
+		sampled bool
+	}{
+		{valid, valid, true},
+		{" " + valid + " ", valid, true},
+		{"00-" + testTraceID + "-" + testParentID + "-00", "00-" + testTraceID + "-" + testParentID + "-00", false},
+
+		// Version 00 must be exactly 55 bytes.
+		{valid + "-", "", false},
+		{valid + "0", "", false},
+		{valid[:54], "", false},
+
+		// Future versions may carry extra fields after a '-'.
+		{"01-" + testTraceID + "-" + testParentID + "-01", "01-" + testTraceID + "-" + testParentID + "-01", true},
+		{"cc-" + testTraceID + "-" + testParentID + "-01-what-the-future-holds", "cc-" + testTraceID + "-" + testParentID + "-01", true},
+		{"cc-" + testTraceID + "-" + testParentID + "-01x", "", false},
+
+		{"ff-" + testTraceID + "-" + testParentID + "-01", "", false},
+		{"00-" + strings.ToUpper(testTraceID) + "-" + testParentID + "-01", "", false},
+		{"00-" + testTraceID + "-" + testParentID + "-0A", "", false},
+		{"0g-" + testTraceID + "-" + testParentID + "-01", "", false},
+		{"00-" + strings.Repeat("0", 32) + "-" + testParentID + "-01", "", false},
+		{"00-" + testTraceID + "-" + strings.Repeat("0", 16) + "-01", "", false},
+		{"00_" + testTraceID + "-" + testParentID + "-01", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		tp, err := ParseTraceParent(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseTraceParent(%q) = %v, want error", tt.in, tp)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTraceParent(%q): %v", tt.in, err)
+			continue
+		}
+		if got := tp.String(); got != tt.want {
+			t.Errorf("ParseTraceParent(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := tp.Sampled(); got != tt.sampled {
+			t.Errorf("ParseTraceParent(%q).Sampled() = %v, want %v", tt.in, got, tt.sampled)
+		}
+	}
+}
+
+// traceStateList returns a tracestate value with n members k0=v0, k1=v1, ...
+func traceStateList(n int) string {
+	var s []string
+	for i := range n {
+		s = append(s, fmt.Sprintf("k%d=v%d", i, i))
+	}
+	return strings.Join(s, ",")
+}
+
+func TestParseTraceState(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // formatted result
+		err  bool
+	}{
+		{in: "", want: ""},
+		{in: "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", want: "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE"},
+		{in: " rojo=1 ,\t, ,congo=2", want: "rojo=1,congo=2"},
+		{in: "tenant@sys=x,0tenant@s=y", want: "tenant@sys=x,0tenant@s=y"},
+		{in: "a/b_c-d*e=v", want: "a/b_c-d*e=v"},
+		{in: "k=a b", want: "k=a b"},
+		{in: traceStateList(maxTraceStateMembers), want: traceStateList(maxTraceStateMembers)},
+
+		{in: traceStateList(maxTraceStateMembers + 1), err: true},
+		{in: "rojo=1,rojo=2", err: true},
+		{in: "t@s=1,t@s=2", err: true},
+		{in: "Rojo=1", err: true},
+		{in: "0rojo=1", err: true},
+		{in: "@s=1", err: true},
+		{in: "t@=1", err: true},
+		{in: "t@0s=1", err: true},
+		{in: "t@" + strings.Repeat("s", 15) + "=1", err: true},
+		{in: strings.Repeat("k", 257) + "=1", err: true},
+		{in: "rojo", err: true},
+		{in: "rojo=", err: true},
+		{in: "rojo=a=b", err: true},
+		{in: "rojo=trailing ", want: "rojo=trailing"},
+		{in: "rojo=\x7f", err: true},
+	}
+	for _, tt := range tests {
+		ts, err := ParseTraceState(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseTraceState(%q) = %q, want error", tt.in, ts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTraceState(%q): %v", tt.in, err)
+			continue
+		}
+		if got := ts.String(); got != tt.want {
+			t.Errorf("ParseTraceState(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTraceStateLarge(t *testing.T) {
+	// An oversized value is rejected without checking every member
+	// against every other for duplicates.
+	const n = 60000
+	start := time.Now()
+	if _, err := ParseTraceState(traceStateList(n)); err == nil {
+		t.Fatalf("ParseTraceState with %d members succeeded, want error", n)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("ParseTraceState with %d members took %v", n, d)
+	}
+}
+
+func TestTraceStateSetDelete(t *testing.T) {
+	ts, err := ParseTraceState("rojo=1,congo=2,t@s=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ts.Get("congo"); got != "2" {
+		t.Errorf("Get(congo) = %q, want 2", got)
+	}
+	if got := ts.Get("missing"); got != "" {
+		t.Errorf("Get(missing) = %q, want empty", got)
+	}
+
+	ts2, err := ts.Set("congo", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ts2.String(), "congo=new,rojo=1,t@s=3"; got != want {
+		t.Errorf("Set(congo) = %q, want %q", got, want)
+	}
+	if got, want := ts.String(), "rojo=1,congo=2,t@s=3"; got != want {
+		t.Errorf("Set modified the original: %q, want %q", got, want)
+	}
+	ts3, err := ts.Set("new", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ts3.String(), "new=x,rojo=1,congo=2,t@s=3"; got != want {
+		t.Errorf("Set(new) = %q, want %q", got, want)
+	}
+	if _, err := ts.Set("Bad", "x"); err == nil {
+		t.Error("Set with invalid key succeeded")
+	}
+	for _, v := range []string{"a,b", "a=b", "trailing ", ""} {
+		if _, err := ts.Set("rojo", v); err == nil {
+			t.Errorf("Set with invalid value %q succeeded", v)
+		}
+	}
+
+	if got, want := ts.Delete("congo").String(), "rojo=1,t@s=3"; got != want {
+		t.Errorf("Delete(congo) = %q, want %q", got, want)
+	}
+	if got, want := ts.Delete("missing").String(), ts.String(); got != want {
+		t.Errorf("Delete(missing) = %q, want %q", got, want)
+	}
+	if got, want := ts.String(), "rojo=1,congo=2,t@s=3"; got != want {
+		t.Errorf("Delete modified the original: %q, want %q", got, want)
+	}
+
+	// Setting a new key on a full list drops the last member.
+	full, err := ParseTraceState(traceStateList(maxTraceStateMembers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err = full.Set("new", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := full.Len(); got != maxTraceStateMembers {
+		t.Errorf("Len after Set on full list = %d, want %d", got, maxTraceStateMembers)
+	}
+	want := "new=x," + traceStateList(maxTraceStateMembers-1)
+	if got := full.String(); got != want {
+		t.Errorf("Set on full list = %q, want %q", got, want)
+	}
+}
+
+func TestParseBaggage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []BaggageMember
+		err  bool
+	}{
+		{in: "", want: nil},
+		{
+			in: "userId=alice, serverNode = DF%2028 ;p1; p2 = v ,isProduction=false",
+			want: []BaggageMember{
+				{Key: "userId", Value: "alice"},
+				{Key: "serverNode", Value: "DF 28", Properties: []string{"p1", "p2=v"}},
+				{Key: "isProduction", Value: "false"},
+			},
+		},
+		{in: "k=%E2%82%AC", want: []BaggageMember{{Key: "k", Value: "€"}}},
+		{in: "k=", want: []BaggageMember{{Key: "k", Value: ""}}},
+		{in: "k=v;p=%20", want: []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p=%20"}}}},
+		{in: "k=v;p=a;b", want: []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p=a", "b"}}}},
+
+		{in: "k=%2", err: true},
+		{in: "k=%", err: true},
+		{in: "k=%zz", err: true},
+		{in: "k=a b", err: true},
+		{in: `k="v"`, err: true},
+		{in: "k", err: true},
+		{in: "k y=v", err: true},
+		{in: "=v", err: true},
+		{in: `k=v;p="x y"`, err: true},
+		{in: "k=v;=x", err: true},
+		{in: "k=v;p q", err: true},
+		{in: "k=" + strings.Repeat("a", maxBaggageSize), err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBaggage(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseBaggage(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseBaggage(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseBaggage(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBaggage(t *testing.T) {
+	tests := []struct {
+		in   []BaggageMember
+		want string
+		err  bool
+	}{
+		{in: nil, want: ""},
+		{
+			in: []BaggageMember{
+				{Key: "a", Value: "b c,d;e=%\"\\€"},
+				{Key: "k", Value: "v", Properties: []string{" p ", "q = r"}},
+			},
+			want: `a=b%20c%2Cd%3Be=%25%22%5C%E2%82%AC,k=v;p;q=r`,
+		},
+		{in: []BaggageMember{{Key: "a,b", Value: "v"}}, err: true},
+		{in: []BaggageMember{{Key: "a=b", Value: "v"}}, err: true},
+		{in: []BaggageMember{{Key: "", Value: "v"}}, err: true},
+		{in: []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p=x y"}}}, err: true},
+		{in: []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p,q"}}}, err: true},
+		{in: []BaggageMember{{Key: "k", Value: strings.Repeat(" ", maxBaggageSize/3+1)}}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := FormatBaggage(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("FormatBaggage(%#v) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FormatBaggage(%#v): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatBaggage(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBaggageRoundTrip(t *testing.T) {
+	members := []BaggageMember{
+		{Key: "a", Value: "100% sure, really; \"quoted\" \\ €\x00\xff"},
+		{Key: "b", Value: "", Properties: []string{"p", "q=%41"}},
+	}
+	s, err := FormatBaggage(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseBaggage(s)
+	if err != nil {
+		t.Fatalf("ParseBaggage(%q): %v", s, err)
+	}
+	if !reflect.DeepEqual(got, members) {
+		t.Errorf("round trip of %q = %#v, want %#v", s, got, members)
+	}
+}
+
+func TestTraceContextContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TraceParentFromContext(ctx); ok {
+		t.Error("TraceParentFromContext on empty context reported ok")
+	}
+	if _, ok := TraceStateFromContext(ctx); ok {
+		t.Error("TraceStateFromContext on empty context reported ok")
+	}
+	if _, ok := BaggageFromContext(ctx); ok {
+		t.Error("BaggageFromContext on empty context reported ok")
+	}
+
+	tp, err := ParseTraceParent("00-" + testTraceID + "-" + testParentID + "-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := ParseTraceState("rojo=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p=1"}}}
+	ctx = ContextWithTraceParent(ctx, tp)
+	ctx = ContextWithTraceState(ctx, ts)
+	ctx = ContextWithBaggage(ctx, members)
+	members[0].Value = "changed"
+	members[0].Properties[0] = "changed"
+
+	if got, ok := TraceParentFromContext(ctx); !ok || got != tp {
+		t.Errorf("TraceParentFromContext = %v, %v; want %v, true", got, ok, tp)
+	}
+	if got, ok := TraceStateFromContext(ctx); !ok || got.String() != "rojo=1" {
+		t.Errorf("TraceStateFromContext = %q, %v; want %q, true", got, ok, "rojo=1")
+	}
+	want := []BaggageMember{{Key: "k", Value: "v", Properties: []string{"p=1"}}}
+	if got, ok := BaggageFromContext(ctx); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("BaggageFromContext = %v, %v; want %v, true", got, ok, want)
+	}
+}